@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ffprobeFormat struct {
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+// ffprobeDuration returns path's duration via `ffprobe -show_format`.
+func ffprobeDuration(ctx context.Context, path string) (time.Duration, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe", "-v", "error", "-show_format", "-of", "json", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe duration for %q: %w", path, err)
+	}
+
+	var res ffprobeFormat
+	if err := json.Unmarshal(out, &res); err != nil {
+		return 0, fmt.Errorf("parse ffprobe duration for %q: %w", path, err)
+	}
+	secs, err := strconv.ParseFloat(res.Format.Duration, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse duration %q for %q: %w", res.Format.Duration, path, err)
+	}
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// sumDurations returns the combined duration of every file, for estimating
+// overall progress of a concat run.
+func sumDurations(ctx context.Context, absFiles []string) (time.Duration, error) {
+	var total time.Duration
+	for _, f := range absFiles {
+		dur, err := ffprobeDuration(ctx, f)
+		if err != nil {
+			return 0, err
+		}
+		total += dur
+	}
+	return total, nil
+}
+
+// escapeFFMetadata escapes a value per ffmpeg's ffmetadata format, where
+// '=', ';', '#', '\', and newlines are significant and must be
+// backslash-escaped to survive as literal characters in a tag value.
+func escapeFFMetadata(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '=', ';', '#', '\\', '\n':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// buildChapterMetadata builds an ffmetadata file (see ffmpeg's "Metadata"
+// docs) describing one chapter per input, titled from its filename, with
+// boundaries computed from each input's cumulative duration.
+func buildChapterMetadata(ctx context.Context, absFiles []string) (string, error) {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+
+	var cursor time.Duration
+	for _, f := range absFiles {
+		dur, err := ffprobeDuration(ctx, f)
+		if err != nil {
+			return "", err
+		}
+		start := cursor
+		cursor += dur
+		title := escapeFFMetadata(filenameFromBasename(filepath.Base(f)))
+		fmt.Fprintf(&b, "[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			start.Milliseconds(), cursor.Milliseconds(), title)
+	}
+	return b.String(), nil
+}
+
+// extractPosterFrame grabs a single frame from videoPath one second in and
+// writes it to posterPath, for use as attached cover art.
+func extractPosterFrame(ctx context.Context, videoPath, posterPath string) error {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-i", videoPath, "-ss", "1", "-frames:v", "1", posterPath)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("extract poster frame from %q: %w: %s", videoPath, err, stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestEscapeFFMetadata(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "no special characters", in: "episode one", want: "episode one"},
+		{name: "equals and semicolon", in: "a=b;c", want: `a\=b\;c`},
+		{name: "hash and backslash", in: "#tag\\path", want: `\#tag\\path`},
+		{name: "newline", in: "line1\nline2", want: "line1\\\nline2"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := escapeFFMetadata(c.in); got != c.want {
+				t.Errorf("escapeFFMetadata(%q) = %q, want %q", c.in, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+)
+
+// Config holds the flags shared by every subcommand.
+type Config struct {
+	Concurrency int
+	OutputDir   string
+	DryRun      bool
+	Overwrite   bool
+	KeepInputs  bool
+	LogLevel    string
+}
+
+func defaultConfig() Config {
+	return Config{
+		Concurrency: runtimeDefaultConcurrency(),
+		OutputDir:   "",
+		DryRun:      false,
+		Overwrite:   false,
+		KeepInputs:  false,
+		LogLevel:    "info",
+	}
+}
+
+// registerSharedFlags wires the flags common to every subcommand into fs and cfg.
+func registerSharedFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.IntVar(&cfg.Concurrency, "concurrency", cfg.Concurrency, "number of ffmpeg jobs to run in parallel")
+	fs.StringVar(&cfg.OutputDir, "output-dir", cfg.OutputDir, "directory to write outputs to (defaults to alongside inputs)")
+	fs.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "print planned actions without running ffmpeg")
+	fs.BoolVar(&cfg.Overwrite, "overwrite", cfg.Overwrite, "overwrite existing output files")
+	fs.BoolVar(&cfg.KeepInputs, "keep-inputs", cfg.KeepInputs, "do not delete input files after a successful merge")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "log level: debug, info, warn, error")
+}
+
+func parseLogLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	return l, nil
+}
+
+func setupLogger(level string) error {
+	l, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: l})))
+	return nil
+}
+
+type subcommand struct {
+	name  string
+	short string
+	// build registers any flags specific to this subcommand on fs (shared
+	// flags are already registered) and returns the function to execute
+	// once fs has been parsed.
+	build func(fs *flag.FlagSet, cfg *Config) func(ctx context.Context, args []string) error
+}
+
+var subcommands = []subcommand{
+	{name: "merge", short: "pair up audio/video files in a directory tree and merge each pair", build: buildMergeCmd},
+	{name: "concat", short: "concatenate videos in order into a single file", build: buildConcatCmd},
+	{name: "probe", short: "print ffprobe stream information for the given files", build: buildProbeCmd},
+	{name: "serve", short: "expose the job API over HTTP (e.g. 'avmerger serve :8080')", build: buildServeCmd},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "avmerger is a tool for merging and concatenating audio/video files with ffmpeg")
+	fmt.Fprintln(os.Stderr, "\nUsage:")
+	fmt.Fprintln(os.Stderr, "  avmerger <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, sc := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", sc.name, sc.short)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'avmerger <command> -h' for flags specific to a command.")
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("no command provided")
+	}
+
+	name := args[0]
+	for _, sc := range subcommands {
+		if sc.name != name {
+			continue
+		}
+
+		cfg := defaultConfig()
+		fs := flag.NewFlagSet(name, flag.ExitOnError)
+		registerSharedFlags(fs, &cfg)
+		exec := sc.build(fs, &cfg)
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := setupLogger(cfg.LogLevel); err != nil {
+			return err
+		}
+		return exec(context.Background(), fs.Args())
+	}
+
+	usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func buildMergeCmd(fs *flag.FlagSet, cfg *Config) func(ctx context.Context, args []string) error {
+	opts := MergeOptions{}
+	fs.StringVar(&opts.Root, "root", ".", "directory to recursively search for audio/video pairs")
+	fs.StringVar(&opts.PairRegex, "pair-regex", "", "regex (with a 'stem' capture group) matching audio filenames, e.g. '(?P<stem>.+)\\.(mp3|m4a|opus)$'")
+	fs.StringVar(&opts.VideoRegex, "video-regex", "", "regex (with a 'stem' capture group) matching video filenames, e.g. '(?P<stem>.+)\\.(mp4|webm|mkv)$'")
+	fs.StringVar(&opts.StripSuffix, "strip-suffix", "", "suffix to strip from both filenames before pairing, e.g. '.video'/'.audio' so foo.video.mp4 pairs with foo.audio.mp3")
+	fs.BoolVar(&opts.FailFast, "fail-fast", false, "cancel in-flight merges as soon as one fails")
+	fs.StringVar(&opts.Format, "format", "table", "report format: table or json")
+
+	return func(ctx context.Context, args []string) error {
+		return mergeAudVid(ctx, *cfg, opts, func(pct float64) {
+			slog.Debug("merge progress", "pct", pct)
+		})
+	}
+}
+
+func buildConcatCmd(fs *flag.FlagSet, cfg *Config) func(ctx context.Context, args []string) error {
+	var output string
+	opts := ConcatOptions{}
+	fs.StringVar(&output, "o", "", "output file path (defaults to 'concat - <first input basename>')")
+	fs.BoolVar(&opts.Chapters, "chapters", true, "add a chapter per input, titled from its filename")
+	fs.BoolVar(&opts.Poster, "poster", false, "attach a poster frame from the first input as cover art (requires a .mkv output)")
+
+	return func(ctx context.Context, args []string) error {
+		return concatVideos(ctx, *cfg, opts, output, args, func(pct float64) {
+			slog.Debug("concat progress", "pct", pct)
+		})
+	}
+}
+
+func buildProbeCmd(fs *flag.FlagSet, cfg *Config) func(ctx context.Context, args []string) error {
+	return func(ctx context.Context, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("probe: no files provided")
+		}
+		for _, path := range args {
+			cmd := exec.CommandContext(ctx, "ffprobe", "-v", "error", "-show_streams", "-of", "json", path)
+			out, err := cmd.Output()
+			if err != nil {
+				return fmt.Errorf("ffprobe failed for %q: %w", path, err)
+			}
+			fmt.Printf("%s:\n%s\n", path, out)
+		}
+		return nil
+	}
+}
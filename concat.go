@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ConcatOptions configures optional extras for the concat subcommand.
+type ConcatOptions struct {
+	Chapters bool
+	Poster   bool
+}
+
+type tmpFile struct {
+	dir string
+
+	file *os.File
+}
+
+func (f *tmpFile) File() *os.File {
+	return f.file
+}
+
+func (f *tmpFile) Create() (*os.File, error) {
+	dir, err := os.MkdirTemp("", "concat-files")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory for list of videos: %v", err)
+	}
+	f.dir = dir
+
+	file, err := os.CreateTemp(dir, "video-file-list")
+	if err != nil {
+		errRmDir := os.Remove(f.dir)
+		if errRmDir != nil {
+			return nil, fmt.Errorf("error creating temp file for the list of videos: %v, error cleaning up temp dir: %v", err, errRmDir)
+		}
+		return nil, fmt.Errorf("error creating temp file for the list of videos: %v", err)
+	}
+
+	f.file = file
+
+	return f.file, nil
+}
+
+func (f *tmpFile) Cleanup() error {
+	var errs []error
+	var err = f.file.Close()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	err = os.RemoveAll(f.dir)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// escapeConcatPath escapes a path per the ffmpeg concat demuxer's quoting
+// rules: each single quote is replaced with the four-character sequence
+// that closes the quoted string, appends an escaped quote, then reopens it.
+func escapeConcatPath(path string) string {
+	return strings.ReplaceAll(path, "'", `'\''`)
+}
+
+// absInputs resolves every file to an absolute path so the generated list
+// file (and any fallback ffmpeg invocation) is independent of cwd.
+func absInputs(files []string) ([]string, error) {
+	abs := make([]string, 0, len(files))
+	for _, f := range files {
+		p, err := filepath.Abs(f)
+		if err != nil {
+			return nil, fmt.Errorf("resolve absolute path for %q: %w", f, err)
+		}
+		abs = append(abs, p)
+	}
+	return abs, nil
+}
+
+func concatInputFiles(absFiles []string) string {
+	mappedFiles := make([]string, 0, len(absFiles))
+	for _, v := range absFiles {
+		mappedFiles = append(mappedFiles, fmt.Sprintf("file 'file:%s'", escapeConcatPath(v)))
+	}
+
+	return strings.Join(mappedFiles, "\n")
+}
+
+// concatInputsCompatible reports whether every file's primary video stream
+// shares the same codec, pixel format, and time base, which is what the
+// concat demuxer requires to safely stream-copy rather than re-encode.
+func concatInputsCompatible(ctx context.Context, absFiles []string) (bool, error) {
+	var first FFProbeStream
+	for i, f := range absFiles {
+		res, err := ffprobeShowStreams(ctx, f)
+		if err != nil {
+			return false, err
+		}
+		vs, ok := res.videoStream()
+		if !ok {
+			return false, fmt.Errorf("no video stream found in %q", f)
+		}
+		if i == 0 {
+			first = vs
+			continue
+		}
+		if vs.CodecName != first.CodecName || vs.PixFmt != first.PixFmt || vs.TimeBase != first.TimeBase {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// concatFilterGraph builds the filter_complex expression that concatenates
+// n inputs' first video+audio stream via the concat filter (used as a
+// re-encoding fallback when inputs aren't compatible for stream copy).
+func concatFilterGraph(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "[%d:v:0][%d:a:0]", i, i)
+	}
+	fmt.Fprintf(&b, "concat=n=%d:v=1:a=1[v][a]", n)
+	return b.String()
+}
+
+func concatOutputPath(cfg Config, output string, files []string) string {
+	if output == "" {
+		output = fmt.Sprintf("concat - %s", filepath.Base(files[0]))
+	}
+	if cfg.OutputDir == "" || filepath.IsAbs(output) {
+		return output
+	}
+	return filepath.Join(cfg.OutputDir, output)
+}
+
+func concatVideos(ctx context.Context, cfg Config, opts ConcatOptions, output string, files []string, report func(pct float64)) error {
+	if report == nil {
+		report = func(float64) {}
+	}
+
+	switch len(files) {
+	case 0:
+		return fmt.Errorf("concat: no video files to concat provided")
+	case 1:
+		return fmt.Errorf("concat: provide more than one video to concat")
+	}
+
+	dst := concatOutputPath(cfg, output, files)
+	if opts.Poster && !strings.EqualFold(filepath.Ext(dst), ".mkv") {
+		return fmt.Errorf("--poster requires a .mkv output (got %q): ffmpeg's -attach only works with the Matroska muxer", dst)
+	}
+	if !cfg.Overwrite {
+		if _, err := os.Stat(dst); err == nil {
+			return fmt.Errorf("output %q already exists, pass --overwrite to replace it", dst)
+		}
+	}
+
+	absFiles, err := absInputs(files)
+	if err != nil {
+		return err
+	}
+
+	compatible, err := concatInputsCompatible(ctx, absFiles)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	var inputCount int
+	var cleanup func()
+	if compatible {
+		list := &tmpFile{}
+		file, err := list.Create()
+		if err != nil {
+			return err
+		}
+		cleanup = func() {
+			if err := list.Cleanup(); err != nil {
+				slog.Error("failed to clean up concat list file", "error", err)
+			}
+		}
+
+		if _, err := file.WriteString(concatInputFiles(absFiles)); err != nil {
+			cleanup()
+			return err
+		}
+
+		args = []string{"-y", "-f", "concat", "-safe", "0", "-i", file.Name()}
+		inputCount = 1
+	} else {
+		slog.Warn("inputs have mismatched codecs/pix_fmt/time_base, falling back to re-encoding concat", "files", files)
+		cleanup = func() {}
+		args = []string{"-y"}
+		for _, f := range absFiles {
+			args = append(args, "-i", f)
+		}
+		args = append(args, "-filter_complex", concatFilterGraph(len(absFiles)), "-map", "[v]", "-map", "[a]")
+		inputCount = len(absFiles)
+	}
+	defer cleanup()
+
+	if cfg.DryRun {
+		if opts.Chapters {
+			args = append(args, "-i", "<chapter-metadata>", "-map_metadata", strconv.Itoa(inputCount))
+			inputCount++
+		}
+		if opts.Poster {
+			args = append(args, "-attach", "<poster-frame.jpg>", "-metadata:s:t:0", "mimetype=image/jpeg")
+		}
+		args = append(args, "-progress", "pipe:1", "-nostats", dst)
+		slog.Info("dry-run: would concat", "files", files, "output", dst)
+		fmt.Println(strings.Join(append([]string{"ffmpeg"}, args...), " "))
+		return nil
+	}
+
+	if opts.Chapters {
+		metadata, err := buildChapterMetadata(ctx, absFiles)
+		if err != nil {
+			return err
+		}
+		chapters := &tmpFile{}
+		chaptersFile, err := chapters.Create()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := chapters.Cleanup(); err != nil {
+				slog.Error("failed to clean up chapter metadata file", "error", err)
+			}
+		}()
+		if _, err := chaptersFile.WriteString(metadata); err != nil {
+			return err
+		}
+
+		args = append(args, "-i", chaptersFile.Name(), "-map_metadata", strconv.Itoa(inputCount))
+		inputCount++
+	}
+
+	if opts.Poster {
+		poster := &tmpFile{}
+		posterFile, err := poster.Create()
+		if err != nil {
+			return err
+		}
+		posterPath := posterFile.Name() + ".jpg"
+		if err := posterFile.Close(); err != nil {
+			return err
+		}
+		defer func() {
+			if err := poster.Cleanup(); err != nil {
+				slog.Error("failed to clean up poster frame", "error", err)
+			}
+		}()
+
+		if err := extractPosterFrame(ctx, absFiles[0], posterPath); err != nil {
+			return err
+		}
+		args = append(args, "-attach", posterPath, "-metadata:s:t:0", "mimetype=image/jpeg")
+	}
+
+	args = append(args, "-progress", "pipe:1", "-nostats", dst)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	cmd.Stderr = os.Stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	totalDuration, err := sumDurations(ctx, absFiles)
+	if err != nil {
+		slog.Warn("failed to compute total duration for progress reporting", "error", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run ffmpeg concat command: %v", err)
+	}
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		watchFFmpegProgress(stdout, totalDuration, report)
+	}()
+	runErr := cmd.Wait()
+	<-progressDone
+	if runErr != nil {
+		return fmt.Errorf("failed to run ffmpeg concat command: %v", runErr)
+	}
+
+	if !cfg.KeepInputs {
+		for _, f := range files {
+			if err := os.Remove(f); err != nil {
+				slog.Error("failed to remove input file", "file", f, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
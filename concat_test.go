@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestEscapeConcatPath(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "no special characters", in: "/videos/clip1.mp4", want: "/videos/clip1.mp4"},
+		{name: "single quote", in: "/videos/clip's.mp4", want: `/videos/clip'\''s.mp4`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := escapeConcatPath(c.in); got != c.want {
+				t.Errorf("escapeConcatPath(%q) = %q, want %q", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestConcatFilterGraph(t *testing.T) {
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{n: 1, want: "[0:v:0][0:a:0]concat=n=1:v=1:a=1[v][a]"},
+		{n: 3, want: "[0:v:0][0:a:0][1:v:0][1:a:0][2:v:0][2:a:0]concat=n=3:v=1:a=1[v][a]"},
+	}
+
+	for _, c := range cases {
+		if got := concatFilterGraph(c.n); got != c.want {
+			t.Errorf("concatFilterGraph(%d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JobStatus describes the lifecycle state of a Job.
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// JobInfo is a point-in-time, JSON-serializable snapshot of a Job.
+type JobInfo struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Status    JobStatus `json:"status"`
+	Progress  float64   `json:"progress"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+	Err       string    `json:"error,omitempty"`
+}
+
+// Job tracks a single merge/concat operation submitted to a JobManager.
+// Callers never get a Job by value; they observe it through JobInfo
+// snapshots taken under mu.
+type Job struct {
+	mu   sync.Mutex
+	info JobInfo
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (j *Job) setProgress(pct float64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.Progress = pct
+}
+
+func (j *Job) snapshot() JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.info
+}
+
+// JobFunc is the unit of work a JobManager executes for a Job; it should
+// honor ctx cancellation and report progress as a 0-100 percentage.
+type JobFunc func(ctx context.Context, report func(pct float64)) error
+
+// JobManager runs JobFuncs asynchronously and tracks their status.
+type JobManager struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+func NewJobManager() *JobManager {
+	return &JobManager{jobs: make(map[string]*Job)}
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("job-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Submit starts fn asynchronously and returns the Job tracking it.
+func (m *JobManager) Submit(ctx context.Context, kind string, fn JobFunc) *Job {
+	ctx, cancel := context.WithCancel(ctx)
+	job := &Job{
+		info:   JobInfo{ID: newJobID(), Kind: kind, Status: JobQueued},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.jobs[job.info.ID] = job
+	m.mu.Unlock()
+
+	go func() {
+		defer close(job.done)
+		defer cancel()
+
+		job.mu.Lock()
+		job.info.Status = JobRunning
+		job.info.StartedAt = time.Now()
+		job.mu.Unlock()
+
+		err := fn(ctx, job.setProgress)
+
+		job.mu.Lock()
+		job.info.EndedAt = time.Now()
+		if err != nil {
+			job.info.Status = JobFailed
+			job.info.Err = err.Error()
+		} else {
+			job.info.Status = JobDone
+			job.info.Progress = 100
+		}
+		job.mu.Unlock()
+
+		info := job.snapshot()
+		if err != nil {
+			slog.Error("job failed", "id", info.ID, "kind", info.Kind, "error", err)
+		} else {
+			slog.Info("job finished", "id", info.ID, "kind", info.Kind)
+		}
+	}()
+
+	return job
+}
+
+// Cancel requests that the job with the given ID stop as soon as possible.
+func (m *JobManager) Cancel(id string) error {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	job.cancel()
+	return nil
+}
+
+// Wait blocks until the job with the given ID finishes and returns its final state.
+func (m *JobManager) Wait(id string) (JobInfo, error) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return JobInfo{}, fmt.Errorf("job %q not found", id)
+	}
+	<-job.done
+	return job.snapshot(), nil
+}
+
+// Get returns the current state of the job with the given ID.
+func (m *JobManager) Get(id string) (JobInfo, error) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return JobInfo{}, fmt.Errorf("job %q not found", id)
+	}
+	return job.snapshot(), nil
+}
+
+// List returns a snapshot of every job known to the manager.
+func (m *JobManager) List() []JobInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]JobInfo, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job.snapshot())
+	}
+	return jobs
+}
+
+// watchFFmpegProgress parses ffmpeg's "-progress pipe:1" key=value stream from r
+// and calls report with a 0-100 percentage derived from out_time_ms and totalDuration.
+// If totalDuration is zero, percentages are not reported.
+func watchFFmpegProgress(r io.Reader, totalDuration time.Duration, report func(pct float64)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "out_time_ms":
+			if totalDuration <= 0 {
+				continue
+			}
+			ms, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				continue
+			}
+			pct := float64(ms) / float64(totalDuration.Microseconds()) * 100
+			if pct > 100 {
+				pct = 100
+			}
+			if pct < 0 {
+				pct = 0
+			}
+			report(pct)
+		case "progress":
+			if strings.TrimSpace(value) == "end" {
+				report(100)
+			}
+		}
+	}
+}
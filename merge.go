@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// MergeOptions configures the merge subcommand's pairing pass; see Pairer.
+type MergeOptions struct {
+	Root        string
+	PairRegex   string
+	VideoRegex  string
+	StripSuffix string
+	FailFast    bool
+	Format      string
+}
+
+func mp3Mp4Merger(ctx context.Context, cfg Config, videoPath, audioPath, destDir string, report func(pct float64)) FileReport {
+	if report == nil {
+		report = func(float64) {}
+	}
+
+	fileReport := FileReport{Video: videoPath, Audio: audioPath}
+	start := time.Now()
+	defer func() { fileReport.Duration = time.Since(start) }()
+
+	fail := func(err error) FileReport {
+		fileReport.Status = FileStatusFailed
+		fileReport.StderrTail = err.Error()
+		return fileReport
+	}
+
+	videoProbe, err := ffprobeShowStreams(ctx, videoPath)
+	if err != nil {
+		return fail(err)
+	}
+	audioProbe, err := ffprobeShowStreams(ctx, audioPath)
+	if err != nil {
+		return fail(err)
+	}
+	videoCodec, audioCodec := videoProbe.codecOfType("video"), audioProbe.codecOfType("audio")
+
+	profile := selectMuxProfile(filepath.Ext(videoPath), filepath.Ext(audioPath))
+	dstFilename := filepath.Join(destDir, MergedFilePrefix+" "+filenameFromBasename(filepath.Base(videoPath))+profile.Container)
+	fileReport.Output = dstFilename
+
+	if cfg.DryRun {
+		ffmpegCmdLine := fmt.Sprintf("ffmpeg -y -i %s -i %s %s %s", videoPath, audioPath, strings.Join(profile.Args(videoCodec, audioCodec), " "), dstFilename)
+		slog.Info("dry-run: would merge", "video", videoPath, "audio", audioPath, "profile", profile.Name, "output", dstFilename)
+		fmt.Println(ffmpegCmdLine)
+		fileReport.Status = FileStatusOK
+		return fileReport
+	}
+
+	if !cfg.Overwrite {
+		if _, err := os.Stat(dstFilename); err == nil {
+			return fail(fmt.Errorf("output %q already exists, pass --overwrite to replace it", dstFilename))
+		}
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fail(fmt.Errorf("create output directory %q: %w", destDir, err))
+	}
+
+	args := append([]string{"-y", "-i", videoPath, "-i", audioPath}, profile.Args(videoCodec, audioCodec)...)
+	args = append(args, "-progress", "pipe:1", "-nostats", dstFilename)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fail(err)
+	}
+	videoDuration, _ := ffprobeDuration(ctx, videoPath)
+
+	slog.Info("merging", "video", videoPath, "audio", audioPath, "profile", profile.Name)
+	if err := cmd.Start(); err != nil {
+		return fail(err)
+	}
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		watchFFmpegProgress(stdout, videoDuration, report)
+	}()
+	runErr := cmd.Wait()
+	<-progressDone
+	if runErr != nil {
+		fileReport.ExitCode = cmd.ProcessState.ExitCode()
+		fileReport.StderrTail = tailString(stderr.String(), 2048)
+		fileReport.Status = FileStatusFailed
+		return fileReport
+	}
+	slog.Info("merged", "video", videoPath, "audio", audioPath, "output", dstFilename)
+
+	if !cfg.KeepInputs {
+		for _, path := range []string{videoPath, audioPath} {
+			if err := os.Remove(path); err != nil {
+				return fail(fmt.Errorf(`failed to remove file "%s": %w`, path, err))
+			}
+			slog.Info("removed", "file", path)
+		}
+	}
+
+	fileReport.Status = FileStatusOK
+	return fileReport
+}
+
+func filenameFromBasename(basename string) string {
+	return strings.TrimSuffix(basename, filepath.Ext(basename))
+}
+
+func newPairerFromOptions(opts MergeOptions, outputDir string) (*Pairer, error) {
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+
+	pairer, err := NewPairer(root)
+	if err != nil {
+		return nil, err
+	}
+	if opts.PairRegex != "" {
+		re, err := regexp.Compile(opts.PairRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --pair-regex: %w", err)
+		}
+		pairer.AudioRegex = re
+	}
+	if opts.VideoRegex != "" {
+		re, err := regexp.Compile(opts.VideoRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --video-regex: %w", err)
+		}
+		pairer.VideoRegex = re
+	}
+	pairer.StripSuffix = opts.StripSuffix
+	pairer.OutputDir = outputDir
+
+	return pairer, nil
+}
+
+func mergeAudVid(ctx context.Context, cfg Config, opts MergeOptions, report func(pct float64)) error {
+	if report == nil {
+		report = func(float64) {}
+	}
+
+	pairer, err := newPairerFromOptions(opts, cfg.OutputDir)
+	if err != nil {
+		return err
+	}
+
+	pairings, err := pairer.Discover()
+	if err != nil {
+		return err
+	}
+	if len(pairings) == 0 {
+		slog.Warn("no audio/video pairs found", "root", pairer.Root)
+		return nil
+	}
+
+	nProc := cfg.Concurrency
+	if nProc <= 0 {
+		nProc = runtimeDefaultConcurrency()
+	}
+
+	reports := make([]FileReport, len(pairings))
+
+	var progressMu sync.Mutex
+	perFile := make([]float64, len(pairings))
+	reportFileProgress := func(i int, pct float64) {
+		progressMu.Lock()
+		perFile[i] = pct
+		var sum float64
+		for _, p := range perFile {
+			sum += p
+		}
+		avg := sum / float64(len(perFile))
+		progressMu.Unlock()
+		report(avg)
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(nProc)
+	for i, pairing := range pairings {
+		i, pairing := i, pairing
+		g.Go(func() error {
+			reports[i] = mp3Mp4Merger(gCtx, cfg, pairing.Video, pairing.Audio, pairing.OutputDir, func(pct float64) {
+				reportFileProgress(i, pct)
+			})
+			if opts.FailFast && reports[i].Status == FileStatusFailed {
+				return fmt.Errorf("%s: %s", pairing.Video, reports[i].StderrTail)
+			}
+			return nil
+		})
+	}
+	// g.Wait's error only matters for --fail-fast cancellation; individual
+	// failures are already captured per-file in reports.
+	_ = g.Wait()
+
+	mergeReport := MergeReport{Files: reports}
+	if opts.Format == "json" {
+		if err := writeMergeReportJSON(os.Stdout, mergeReport); err != nil {
+			return err
+		}
+	} else {
+		writeMergeReportTable(os.Stdout, mergeReport)
+	}
+
+	if mergeReport.Failed() {
+		return fmt.Errorf("merge: %d file(s) failed", countFailed(mergeReport))
+	}
+	return nil
+}
+
+func countFailed(report MergeReport) int {
+	n := 0
+	for _, f := range report.Files {
+		if f.Status == FileStatusFailed {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package main
+
+// MuxProfile describes how mp3Mp4Merger should combine a given video/audio
+// container pair into an output file, including the codec-aware choice
+// between a fast stream copy and a re-encode.
+type MuxProfile struct {
+	Name string
+	// VideoExt/AudioExt constrain which input containers this profile
+	// applies to; "" matches any extension.
+	VideoExt, AudioExt string
+	// AudioExtExclude excludes an audio extension that would otherwise
+	// match, so a more specific profile for that extension (e.g.
+	// mkv-opus-copy for ".opus") is tried instead; "" excludes nothing.
+	AudioExtExclude string
+	// Container is the extension written for the merged output.
+	Container string
+	// Args returns the ffmpeg arguments to place between the two "-i"
+	// inputs and the output path, chosen from the codecs ffprobe detected.
+	Args func(videoCodec, audioCodec string) []string
+}
+
+// muxProfiles is checked in order; the last entry is a catch-all re-encode
+// fallback so selectMuxProfile always returns something usable.
+var muxProfiles = []MuxProfile{
+	{
+		Name: "mp4-copy", VideoExt: ".mp4", AudioExtExclude: ".opus", Container: ".mp4",
+		Args: func(videoCodec, audioCodec string) []string {
+			if isMP4CompatibleVideo(videoCodec) && audioCodec == "aac" {
+				return []string{"-c", "copy"}
+			}
+			return []string{"-c:v", "libx264", "-c:a", "aac"}
+		},
+	},
+	{
+		Name: "mkv-opus-copy", AudioExt: ".opus", Container: ".mkv",
+		Args: func(videoCodec, audioCodec string) []string {
+			if audioCodec == "opus" {
+				return []string{"-c", "copy"}
+			}
+			return []string{"-c:v", "copy", "-c:a", "libopus"}
+		},
+	},
+	{
+		Name: "webm-opus", VideoExt: ".webm", Container: ".webm",
+		Args: func(videoCodec, audioCodec string) []string {
+			if isWebmCompatibleVideo(videoCodec) && audioCodec == "opus" {
+				return []string{"-c", "copy"}
+			}
+			return []string{"-c:v", "libvpx-vp9", "-c:a", "libopus"}
+		},
+	},
+	{
+		Name: "reencode-fallback", Container: ".mp4",
+		Args: func(videoCodec, audioCodec string) []string {
+			return []string{"-c:v", "libx264", "-c:a", "aac"}
+		},
+	},
+}
+
+func isMP4CompatibleVideo(codec string) bool {
+	switch codec {
+	case "h264", "hevc", "av1":
+		return true
+	}
+	return false
+}
+
+func isWebmCompatibleVideo(codec string) bool {
+	switch codec {
+	case "vp8", "vp9", "av1":
+		return true
+	}
+	return false
+}
+
+// selectMuxProfile picks the first profile whose container constraints
+// match videoExt/audioExt, falling back to the re-encode profile.
+func selectMuxProfile(videoExt, audioExt string) MuxProfile {
+	for _, p := range muxProfiles[:len(muxProfiles)-1] {
+		if p.VideoExt != "" && p.VideoExt != videoExt {
+			continue
+		}
+		if p.AudioExt != "" && p.AudioExt != audioExt {
+			continue
+		}
+		if p.AudioExtExclude != "" && p.AudioExtExclude == audioExt {
+			continue
+		}
+		return p
+	}
+	return muxProfiles[len(muxProfiles)-1]
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestSelectMuxProfile(t *testing.T) {
+	cases := []struct {
+		name               string
+		videoExt, audioExt string
+		want               string
+	}{
+		{name: "mp4+aac", videoExt: ".mp4", audioExt: ".m4a", want: "mp4-copy"},
+		{name: "mkv+opus", videoExt: ".mkv", audioExt: ".opus", want: "mkv-opus-copy"},
+		{name: "mp4 video with opus audio prefers the opus remux", videoExt: ".mp4", audioExt: ".opus", want: "mkv-opus-copy"},
+		{name: "webm", videoExt: ".webm", audioExt: ".ogg", want: "webm-opus"},
+		{name: "unrecognized pair falls back", videoExt: ".avi", audioExt: ".wav", want: "reencode-fallback"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := selectMuxProfile(c.videoExt, c.audioExt); got.Name != c.want {
+				t.Errorf("selectMuxProfile(%q, %q).Name = %q, want %q", c.videoExt, c.audioExt, got.Name, c.want)
+			}
+		})
+	}
+}
+
+func TestMkvOpusCopyReencodesNonOpusAudio(t *testing.T) {
+	profile := selectMuxProfile(".mkv", ".opus")
+
+	if got := profile.Args("h264", "opus"); len(got) != 2 || got[0] != "-c" || got[1] != "copy" {
+		t.Errorf("Args(h264, opus) = %v, want [-c copy]", got)
+	}
+
+	got := profile.Args("h264", "aac")
+	want := []string{"-c:v", "copy", "-c:a", "libopus"}
+	if len(got) != len(want) {
+		t.Fatalf("Args(h264, aac) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Args(h264, aac) = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultAudioRegex = `(?P<stem>.+)\.(mp3|m4a|opus|aac)$`
+	defaultVideoRegex = `(?P<stem>.+)\.(mp4|webm|mkv)$`
+)
+
+// Pairer walks a directory tree and groups audio/video files into Pairings
+// based on a shared "stem" extracted from their filenames.
+type Pairer struct {
+	Root        string
+	AudioRegex  *regexp.Regexp
+	VideoRegex  *regexp.Regexp
+	StripSuffix string
+	// OutputDir mirrors Root's tree under this directory when set; when
+	// empty, each merged output is written alongside its video input.
+	OutputDir string
+}
+
+// Pairing is one matched audio+video file ready to be merged.
+type Pairing struct {
+	Video, Audio string
+	// OutputDir is the directory the merged file should be written to.
+	OutputDir string
+}
+
+// NewPairer builds a Pairer rooted at root using the default audio/video
+// pairing regexes.
+func NewPairer(root string) (*Pairer, error) {
+	audioRe, err := regexp.Compile(defaultAudioRegex)
+	if err != nil {
+		return nil, fmt.Errorf("compile default audio regex: %w", err)
+	}
+	videoRe, err := regexp.Compile(defaultVideoRegex)
+	if err != nil {
+		return nil, fmt.Errorf("compile default video regex: %w", err)
+	}
+	return &Pairer{Root: root, AudioRegex: audioRe, VideoRegex: videoRe}, nil
+}
+
+// stem extracts the pairing key for name using re's "stem" capture group
+// (falling back to the name without its extension), with StripSuffix
+// trimmed off so e.g. "foo.video.mp4" pairs with "foo.audio.mp3" when
+// StripSuffix is ".video"/".audio".
+func (p *Pairer) stem(re *regexp.Regexp, name string) (string, bool) {
+	match := re.FindStringSubmatch(name)
+	if match == nil {
+		return "", false
+	}
+
+	stem := filenameFromBasename(name)
+	if idx := re.SubexpIndex("stem"); idx >= 0 && idx < len(match) {
+		stem = match[idx]
+	}
+	stem = strings.TrimSuffix(stem, p.StripSuffix)
+	return stem, true
+}
+
+// Discover walks Root recursively and returns every audio/video pair found,
+// in a deterministic (lexically sorted) order.
+func (p *Pairer) Discover() ([]Pairing, error) {
+	type group struct {
+		audio, video string
+	}
+	groups := make(map[string]*group)
+
+	err := filepath.WalkDir(p.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), MergedFilePrefix) {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if stem, ok := p.stem(p.AudioRegex, d.Name()); ok {
+			key := dir + "\x00" + stem
+			g := groups[key]
+			if g == nil {
+				g = &group{}
+				groups[key] = g
+			}
+			g.audio = path
+			return nil
+		}
+		if stem, ok := p.stem(p.VideoRegex, d.Name()); ok {
+			key := dir + "\x00" + stem
+			g := groups[key]
+			if g == nil {
+				g = &group{}
+				groups[key] = g
+			}
+			g.video = path
+			return nil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %q: %w", p.Root, err)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairings := make([]Pairing, 0, len(groups))
+	for _, key := range keys {
+		g := groups[key]
+		if g.audio == "" || g.video == "" {
+			continue
+		}
+		pairings = append(pairings, Pairing{
+			Video:     g.video,
+			Audio:     g.audio,
+			OutputDir: p.outputDirFor(g.video),
+		})
+	}
+	return pairings, nil
+}
+
+// outputDirFor mirrors videoPath's directory (relative to Root) under
+// OutputDir, or colocates with the video when OutputDir is unset.
+func (p *Pairer) outputDirFor(videoPath string) string {
+	dir := filepath.Dir(videoPath)
+	if p.OutputDir == "" {
+		return dir
+	}
+	rel, err := filepath.Rel(p.Root, dir)
+	if err != nil {
+		rel = "."
+	}
+	return filepath.Join(p.OutputDir, rel)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPairerStem(t *testing.T) {
+	p, err := NewPairer(".")
+	if err != nil {
+		t.Fatalf("NewPairer: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		filename string
+		strip    string
+		useAudio bool
+		wantStem string
+		wantOK   bool
+	}{
+		{name: "audio match", filename: "episode1.mp3", useAudio: true, wantStem: "episode1", wantOK: true},
+		{name: "video match", filename: "episode1.mp4", useAudio: false, wantStem: "episode1", wantOK: true},
+		{name: "no match", filename: "episode1.txt", useAudio: true, wantOK: false},
+		{name: "strip suffix", filename: "foo.audio.mp3", strip: ".audio", useAudio: true, wantStem: "foo", wantOK: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p.StripSuffix = c.strip
+			re := p.VideoRegex
+			if c.useAudio {
+				re = p.AudioRegex
+			}
+			stem, ok := p.stem(re, c.filename)
+			if ok != c.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, c.wantOK)
+			}
+			if ok && stem != c.wantStem {
+				t.Fatalf("stem = %q, want %q", stem, c.wantStem)
+			}
+		})
+	}
+}
+
+func TestPairerDiscover(t *testing.T) {
+	root := t.TempDir()
+	files := []string{"episode1.mp4", "episode1.mp3", "episode2.mkv", "episode2.opus", "notes.txt"}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(root, f), nil, 0o644); err != nil {
+			t.Fatalf("write %q: %v", f, err)
+		}
+	}
+
+	p, err := NewPairer(root)
+	if err != nil {
+		t.Fatalf("NewPairer: %v", err)
+	}
+
+	pairings, err := p.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(pairings) != 2 {
+		t.Fatalf("got %d pairings, want 2: %+v", len(pairings), pairings)
+	}
+	if filepath.Base(pairings[0].Video) != "episode1.mp4" || filepath.Base(pairings[0].Audio) != "episode1.mp3" {
+		t.Errorf("pairings[0] = %+v, want episode1 pair", pairings[0])
+	}
+	if filepath.Base(pairings[1].Video) != "episode2.mkv" || filepath.Base(pairings[1].Audio) != "episode2.opus" {
+		t.Errorf("pairings[1] = %+v, want episode2 pair", pairings[1])
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// FFProbeStream is the subset of ffprobe's per-stream JSON fields we care about.
+type FFProbeStream struct {
+	CodecType string `json:"codec_type"`
+	CodecName string `json:"codec_name"`
+	PixFmt    string `json:"pix_fmt,omitempty"`
+	TimeBase  string `json:"time_base,omitempty"`
+}
+
+// FFProbeResult is the top-level shape of `ffprobe -show_streams -of json`.
+type FFProbeResult struct {
+	Streams []FFProbeStream `json:"streams"`
+}
+
+// codecOfType returns the codec name of the first stream of the given type
+// ("video" or "audio"), or "" if there is none.
+func (r FFProbeResult) codecOfType(kind string) string {
+	for _, s := range r.Streams {
+		if s.CodecType == kind {
+			return s.CodecName
+		}
+	}
+	return ""
+}
+
+// videoStream returns the first video stream, if any.
+func (r FFProbeResult) videoStream() (FFProbeStream, bool) {
+	for _, s := range r.Streams {
+		if s.CodecType == "video" {
+			return s, true
+		}
+	}
+	return FFProbeStream{}, false
+}
+
+// ffprobeShowStreams runs ffprobe against path and parses its stream list,
+// so callers can make decisions based on actual codecs rather than the
+// file extension.
+func ffprobeShowStreams(ctx context.Context, path string) (FFProbeResult, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe", "-v", "error", "-show_streams", "-of", "json", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return FFProbeResult{}, fmt.Errorf("ffprobe %q: %w", path, err)
+	}
+
+	var res FFProbeResult
+	if err := json.Unmarshal(out, &res); err != nil {
+		return FFProbeResult{}, fmt.Errorf("parse ffprobe output for %q: %w", path, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FileStatus is the outcome of merging a single audio/video pair.
+type FileStatus string
+
+const (
+	FileStatusOK     FileStatus = "ok"
+	FileStatusFailed FileStatus = "failed"
+)
+
+// FileReport is the per-file outcome of a merge, suitable for rendering as
+// a table or serializing as JSON.
+type FileReport struct {
+	Video      string        `json:"video"`
+	Audio      string        `json:"audio"`
+	Output     string        `json:"output,omitempty"`
+	Status     FileStatus    `json:"status"`
+	Duration   time.Duration `json:"duration"`
+	ExitCode   int           `json:"exit_code,omitempty"`
+	StderrTail string        `json:"stderr_tail,omitempty"`
+}
+
+// MergeReport aggregates the FileReports produced by a merge run.
+type MergeReport struct {
+	Files []FileReport `json:"files"`
+}
+
+// Failed reports whether any file in the report failed to merge.
+func (r MergeReport) Failed() bool {
+	for _, f := range r.Files {
+		if f.Status == FileStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func writeMergeReportJSON(w io.Writer, report MergeReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func writeMergeReportTable(w io.Writer, report MergeReport) {
+	fmt.Fprintf(w, "%-8s %-40s %-40s %-10s %s\n", "STATUS", "VIDEO", "AUDIO", "DURATION", "DETAIL")
+	for _, f := range report.Files {
+		detail := f.Output
+		if f.Status == FileStatusFailed {
+			detail = fmt.Sprintf("exit %d: %s", f.ExitCode, stderrTailOneLine(f.StderrTail))
+		}
+		fmt.Fprintf(w, "%-8s %-40s %-40s %-10s %s\n", f.Status, f.Video, f.Audio, f.Duration.Round(time.Millisecond), detail)
+	}
+}
+
+// tailString keeps the last n bytes of s, a cheap way to bound how much
+// ffmpeg stderr we carry around per failed file.
+func tailString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
+
+func stderrTailOneLine(s string) string {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '\n' {
+			return s[i+1:]
+		}
+	}
+	return s
+}
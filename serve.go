@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// mergeRequest is the JSON body accepted by POST /jobs/merge.
+type mergeRequest struct {
+	Root       string `json:"root"`
+	OutputDir  string `json:"output_dir"`
+	Overwrite  bool   `json:"overwrite"`
+	KeepInputs bool   `json:"keep_inputs"`
+}
+
+// concatRequest is the JSON body accepted by POST /jobs/concat.
+type concatRequest struct {
+	Files      []string `json:"files"`
+	Output     string   `json:"output"`
+	OutputDir  string   `json:"output_dir"`
+	Overwrite  bool     `json:"overwrite"`
+	KeepInputs bool     `json:"keep_inputs"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newServeMux(jm *JobManager) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+
+		switch {
+		case r.Method == http.MethodGet:
+			job, err := jm.Get(id)
+			if err != nil {
+				writeError(w, http.StatusNotFound, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, job)
+		case r.Method == http.MethodPost && strings.HasSuffix(id, "/cancel"):
+			id = strings.TrimSuffix(id, "/cancel")
+			if err := jm.Cancel(id); err != nil {
+				writeError(w, http.StatusNotFound, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, http.StatusOK, jm.List())
+	})
+
+	mux.HandleFunc("/jobs/merge", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req mergeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		cfg := defaultConfig()
+		cfg.OutputDir = req.OutputDir
+		cfg.Overwrite = req.Overwrite
+		cfg.KeepInputs = req.KeepInputs
+
+		root := req.Root
+		if root == "" {
+			root = "."
+		}
+		job := jm.Submit(context.WithoutCancel(r.Context()), "merge", func(ctx context.Context, report func(float64)) error {
+			return mergeAudVid(ctx, cfg, MergeOptions{Root: root}, report)
+		})
+		writeJSON(w, http.StatusAccepted, job.snapshot())
+	})
+
+	mux.HandleFunc("/jobs/concat", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req concatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		cfg := defaultConfig()
+		cfg.OutputDir = req.OutputDir
+		cfg.Overwrite = req.Overwrite
+		cfg.KeepInputs = req.KeepInputs
+
+		job := jm.Submit(context.WithoutCancel(r.Context()), "concat", func(ctx context.Context, report func(float64)) error {
+			return concatVideos(ctx, cfg, ConcatOptions{Chapters: true}, req.Output, req.Files, report)
+		})
+		writeJSON(w, http.StatusAccepted, job.snapshot())
+	})
+
+	return mux
+}
+
+func buildServeCmd(fs *flag.FlagSet, cfg *Config) func(ctx context.Context, args []string) error {
+	return func(ctx context.Context, args []string) error {
+		addr := ":8080"
+		if len(args) > 0 {
+			addr = args[0]
+		}
+
+		jm := NewJobManager()
+		srv := &http.Server{
+			Addr:    addr,
+			Handler: newServeMux(jm),
+			BaseContext: func(net.Listener) context.Context {
+				return ctx
+			},
+		}
+
+		slog.Info("serving job API", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil {
+			return fmt.Errorf("serve: %w", err)
+		}
+		return nil
+	}
+}